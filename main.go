@@ -1,12 +1,20 @@
 package main
 
 import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -17,21 +25,56 @@ const (
 	AppAuthor  = "https://github.com/wellsgz/syslog-sender"
 )
 
+// NilValue is the RFC 5424 NILVALUE used for absent fields
+const NilValue = "-"
+
+// stringSliceFlag collects repeated occurrences of a flag into a slice
+type stringSliceFlag []string
+
+func (f *stringSliceFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringSliceFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
 // SyslogConfig holds the configuration for the syslog message
 type SyslogConfig struct {
-	Address   string
-	Port      int
-	Transport string
-	Facility  int
-	Severity  int
-	Message   string
-	Hostname  string
-	Program   string
+	Address        string
+	Port           int
+	Transport      string
+	Facility       int
+	Severity       int
+	Message        string
+	Hostname       string
+	Program        string
+	Format         string
+	MsgID          string
+	StructuredData []string
+	Framing        string
+	TLSCACert      string
+	TLSCert        string
+	TLSKey         string
+	TLSServerName  string
+	TLSSkipVerify  bool
+	CEFVendor      string
+	CEFProduct     string
+	CEFVersion     string
+	SignatureID    string
+	Name           string
+	Extensions     []string
 }
 
-// SyslogClient handles sending syslog messages
+// SyslogClient handles sending syslog messages. It holds a persistent
+// connection, established lazily on first Send, which is reused across
+// calls and automatically redialed once on a write failure.
 type SyslogClient struct {
 	config SyslogConfig
+
+	mu   sync.Mutex
+	conn net.Conn
 }
 
 // NewSyslogClient creates a new syslog client with the given configuration
@@ -39,18 +82,34 @@ func NewSyslogClient(config SyslogConfig) *SyslogClient {
 	return &SyslogClient{config: config}
 }
 
+// validateFacility checks that facility falls within the RFC 3164/5424 range
+func validateFacility(facility int) error {
+	if facility < 0 || facility > 23 {
+		return fmt.Errorf("facility must be between 0 and 23")
+	}
+	return nil
+}
+
+// validateSeverity checks that severity falls within the RFC 3164/5424 range
+func validateSeverity(severity int) error {
+	if severity < 0 || severity > 7 {
+		return fmt.Errorf("severity must be between 0 and 7")
+	}
+	return nil
+}
+
 // validateConfig validates the syslog configuration
 func (s *SyslogClient) validateConfig() error {
 	if s.config.Message == "" {
 		return fmt.Errorf("message is required")
 	}
 
-	if s.config.Facility < 0 || s.config.Facility > 23 {
-		return fmt.Errorf("facility must be between 0 and 23")
+	if err := validateFacility(s.config.Facility); err != nil {
+		return err
 	}
 
-	if s.config.Severity < 0 || s.config.Severity > 7 {
-		return fmt.Errorf("severity must be between 0 and 7")
+	if err := validateSeverity(s.config.Severity); err != nil {
+		return err
 	}
 
 	if s.config.Port < 1 || s.config.Port > 65535 {
@@ -58,134 +117,704 @@ func (s *SyslogClient) validateConfig() error {
 	}
 
 	transport := strings.ToLower(s.config.Transport)
-	if transport != "udp" && transport != "tcp" {
-		return fmt.Errorf("transport must be 'udp' or 'tcp'")
+	if transport != "udp" && transport != "tcp" && transport != "tcp+tls" {
+		return fmt.Errorf("transport must be 'udp', 'tcp', or 'tcp+tls'")
 	}
 	s.config.Transport = transport
 
-	return nil
-}
+	format := strings.ToLower(s.config.Format)
+	if format == "" {
+		format = "rfc3164"
+	}
+	if _, ok := formatterRegistry[format]; !ok {
+		return fmt.Errorf("format must be one of: %s", strings.Join(registeredFormatterNames(), ", "))
+	}
+	s.config.Format = format
 
-// formatMessage creates a RFC 3164 compliant syslog message
-func (s *SyslogClient) formatMessage() (string, error) {
-	// Calculate priority: Facility * 8 + Severity
-	priority := s.config.Facility*8 + s.config.Severity
+	framing := strings.ToLower(s.config.Framing)
+	if framing == "" {
+		framing = "non-transparent"
+	}
+	if framing != "non-transparent" && framing != "octet-counting" {
+		return fmt.Errorf("framing must be 'octet-counting' or 'non-transparent'")
+	}
+	s.config.Framing = framing
 
-	// Get current timestamp in RFC 3164 format
-	timestamp := time.Now().Format("Jan  2 15:04:05")
+	return nil
+}
 
-	// Get hostname (use custom hostname if provided, otherwise system hostname)
-	var hostname string
-	if s.config.Hostname != "" {
-		hostname = s.config.Hostname
-	} else {
+// resolveHostname returns the configured hostname, falling back to the
+// system hostname and finally "localhost", with spaces replaced by hyphens
+func resolveHostname(cfg SyslogConfig) string {
+	hostname := cfg.Hostname
+	if hostname == "" {
 		var err error
 		hostname, err = os.Hostname()
 		if err != nil {
 			hostname = "localhost"
 		}
 	}
+	return strings.ReplaceAll(hostname, " ", "-")
+}
 
-	// Replace spaces with hyphens in hostname to prevent syslog parsing issues
-	hostname = strings.ReplaceAll(hostname, " ", "-")
-
-	// Get program/tag (use custom program if provided, otherwise default)
-	var program string
-	if s.config.Program != "" {
-		program = s.config.Program
-	} else {
+// resolveProgram returns the configured program/tag, falling back to the
+// application name, with spaces replaced by hyphens
+func resolveProgram(cfg SyslogConfig) string {
+	program := cfg.Program
+	if program == "" {
 		program = "syslog-sender"
 	}
+	return strings.ReplaceAll(program, " ", "-")
+}
+
+// Formatter renders a syslog wire message from the given configuration and
+// timestamp. Third-party formatters can be added by calling RegisterFormatter
+// from an importing package's init function.
+type Formatter interface {
+	Format(cfg SyslogConfig, now time.Time) (string, error)
+}
+
+// formatterRegistry holds the formatters available via the -format flag
+var formatterRegistry = make(map[string]Formatter)
+
+// RegisterFormatter makes a Formatter available under the given -format name
+func RegisterFormatter(name string, formatter Formatter) {
+	formatterRegistry[strings.ToLower(name)] = formatter
+}
+
+// registeredFormatterNames returns the registered format names, sorted, for
+// use in usage text and validation errors
+func registeredFormatterNames() []string {
+	names := make([]string, 0, len(formatterRegistry))
+	for name := range formatterRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterFormatter("rfc3164", RFC3164Formatter{})
+	RegisterFormatter("rfc5424", RFC5424Formatter{})
+	RegisterFormatter("rfc5424micro", RFC5424Formatter{Micro: true})
+	RegisterFormatter("cef", CEFFormatter{})
+	RegisterFormatter("leef", LEEFFormatter{})
+}
+
+// formatMessage formats the configured message according to the configured format
+func (s *SyslogClient) formatMessage() (string, error) {
+	return s.formatMessageText(s.config.Message)
+}
+
+// formatMessageText formats an arbitrary message text according to the
+// configured format, using the rest of the config (facility, severity,
+// hostname, etc.) unchanged. This lets SendMany format a distinct message
+// per call while reusing the same client configuration.
+func (s *SyslogClient) formatMessageText(text string) (string, error) {
+	cfg := s.config
+	cfg.Message = text
+	if cfg.Format == "" {
+		cfg.Format = "rfc3164"
+	}
+
+	formatter, ok := formatterRegistry[strings.ToLower(cfg.Format)]
+	if !ok {
+		return "", fmt.Errorf("unsupported format: %s", cfg.Format)
+	}
+
+	return formatter.Format(cfg, time.Now())
+}
 
-	// Replace spaces with hyphens in program to prevent syslog parsing issues
-	program = strings.ReplaceAll(program, " ", "-")
+// RFC3164Formatter renders RFC 3164 ("BSD syslog") messages
+type RFC3164Formatter struct{}
+
+// Format implements Formatter
+func (RFC3164Formatter) Format(cfg SyslogConfig, now time.Time) (string, error) {
+	priority := cfg.Facility*8 + cfg.Severity
+	timestamp := now.Format("Jan  2 15:04:05")
+	hostname := resolveHostname(cfg)
+	program := resolveProgram(cfg)
 
 	// Format: <PRI>TIMESTAMP HOSTNAME TAG: MESSAGE
 	message := fmt.Sprintf("<%d>%s %s %s: %s",
-		priority, timestamp, hostname, program, s.config.Message)
+		priority, timestamp, hostname, program, cfg.Message)
 
 	return message, nil
 }
 
-// SendUDP sends the syslog message using UDP
-func (s *SyslogClient) SendUDP(message string) error {
-	// Resolve UDP address
-	serverAddr := fmt.Sprintf("%s:%d", s.config.Address, s.config.Port)
-	udpAddr, err := net.ResolveUDPAddr("udp", serverAddr)
+// RFC5424Formatter renders RFC 5424 structured syslog messages. Micro
+// selects RFC3339Nano timestamps (rfc5424micro) instead of RFC3339.
+type RFC5424Formatter struct {
+	Micro bool
+}
+
+// Format implements Formatter. The MSG is prefixed with a BOM when it
+// contains non-ASCII content, per the RFC 5424 recommendation for
+// non-ASCII MSG encodings.
+func (f RFC5424Formatter) Format(cfg SyslogConfig, now time.Time) (string, error) {
+	priority := cfg.Facility*8 + cfg.Severity
+
+	timestampFormat := time.RFC3339
+	if f.Micro {
+		timestampFormat = time.RFC3339Nano
+	}
+	timestamp := now.Format(timestampFormat)
+
+	hostname := resolveHostname(cfg)
+	appName := resolveProgram(cfg)
+	procID := strconv.Itoa(os.Getpid())
+
+	msgID := cfg.MsgID
+	if msgID == "" {
+		msgID = NilValue
+	}
+
+	structuredData, err := buildStructuredData(cfg.StructuredData)
 	if err != nil {
-		return fmt.Errorf("failed to resolve UDP address %s: %v", serverAddr, err)
+		return "", fmt.Errorf("failed to build structured data: %v", err)
+	}
+
+	msg := cfg.Message
+	if !isASCII(msg) {
+		msg = "\xEF\xBB\xBF" + msg
 	}
 
-	// Create UDP connection
-	conn, err := net.DialUDP("udp", nil, udpAddr)
+	message := fmt.Sprintf("<%d>1 %s %s %s %s %s %s %s",
+		priority, timestamp, hostname, appName, procID, msgID, structuredData, msg)
+
+	return message, nil
+}
+
+// CEFFormatter renders ArcSight Common Event Format messages, carried
+// inside an RFC 3164 envelope as is conventional for CEF-over-syslog
+type CEFFormatter struct{}
+
+// Format implements Formatter
+func (CEFFormatter) Format(cfg SyslogConfig, now time.Time) (string, error) {
+	priority := cfg.Facility*8 + cfg.Severity
+	timestamp := now.Format("Jan  2 15:04:05")
+	hostname := resolveHostname(cfg)
+
+	extension, err := buildExtension(cfg, " ")
 	if err != nil {
-		return fmt.Errorf("failed to connect to UDP server: %v", err)
+		return "", err
 	}
-	defer conn.Close()
 
-	// Set write timeout
-	conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	body := fmt.Sprintf("CEF:0|%s|%s|%s|%s|%s|%d|%s",
+		escapeCEFHeader(cfg.CEFVendor), escapeCEFHeader(cfg.CEFProduct), escapeCEFHeader(cfg.CEFVersion),
+		escapeCEFHeader(cfg.SignatureID), escapeCEFHeader(cfg.Name), cfg.Severity, extension)
 
-	// Send message
-	_, err = conn.Write([]byte(message))
+	return fmt.Sprintf("<%d>%s %s %s", priority, timestamp, hostname, body), nil
+}
+
+// LEEFFormatter renders QRadar Log Event Extended Format messages, carried
+// inside an RFC 3164 envelope as is conventional for LEEF-over-syslog
+type LEEFFormatter struct{}
+
+// Format implements Formatter
+func (LEEFFormatter) Format(cfg SyslogConfig, now time.Time) (string, error) {
+	priority := cfg.Facility*8 + cfg.Severity
+	timestamp := now.Format("Jan  2 15:04:05")
+	hostname := resolveHostname(cfg)
+
+	extension, err := buildExtension(cfg, "^")
 	if err != nil {
-		return fmt.Errorf("failed to send UDP message: %v", err)
+		return "", err
 	}
 
-	return nil
+	body := fmt.Sprintf("LEEF:2.0|%s|%s|%s|%s|^|%s",
+		escapeCEFHeader(cfg.CEFVendor), escapeCEFHeader(cfg.CEFProduct), escapeCEFHeader(cfg.CEFVersion),
+		escapeCEFHeader(cfg.SignatureID), extension)
+
+	return fmt.Sprintf("<%d>%s %s %s", priority, timestamp, hostname, body), nil
+}
+
+// buildExtension assembles the CEF/LEEF extension field from the repeated
+// -ext key=value flags, appending the configured message as "msg=", and
+// joins the resulting key=value pairs with delimiter (CEF uses a space,
+// LEEF the declared "^" delimiter)
+func buildExtension(cfg SyslogConfig, delimiter string) (string, error) {
+	var parts []string
+	for _, raw := range cfg.Extensions {
+		kv := strings.SplitN(raw, "=", 2)
+		if len(kv) != 2 {
+			return "", fmt.Errorf("invalid extension %q, expected key=value", raw)
+		}
+		parts = append(parts, kv[0]+"="+escapeCEFExtension(kv[1]))
+	}
+	if cfg.Message != "" {
+		parts = append(parts, "msg="+escapeCEFExtension(cfg.Message))
+	}
+	return strings.Join(parts, delimiter), nil
+}
+
+// escapeCEFHeader backslash-escapes the characters CEF requires escaped in
+// header fields: '\\' and '|'
+func escapeCEFHeader(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+	return replacer.Replace(value)
+}
+
+// escapeCEFExtension backslash-escapes the characters CEF requires escaped
+// in extension values: '\\', '=', '|', and newlines
+func escapeCEFExtension(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `=`, `\=`, `|`, `\|`, "\n", `\n`, "\r", `\r`)
+	return replacer.Replace(value)
+}
+
+// isASCII reports whether s contains only ASCII bytes
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] > 127 {
+			return false
+		}
+	}
+	return true
+}
+
+// buildStructuredData assembles RFC 5424 STRUCTURED-DATA from the raw
+// "id@ent key=\"val\" key2=\"val2\"" entries passed via repeated -sd flags.
+// It returns the NILVALUE when no entries are given.
+func buildStructuredData(entries []string) (string, error) {
+	if len(entries) == 0 {
+		return NilValue, nil
+	}
+
+	var b strings.Builder
+	for _, entry := range entries {
+		element, err := formatSDElement(entry)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(element)
+	}
+
+	return b.String(), nil
+}
+
+// formatSDElement parses a single "id@ent key=\"val\"" entry and renders it
+// as an RFC 5424 SD-ELEMENT, escaping '\\', '"' and ']' in each PARAM-VALUE.
+func formatSDElement(entry string) (string, error) {
+	fields := splitSDFields(strings.TrimSpace(entry))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty structured data entry")
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	b.WriteString(fields[0])
+
+	for _, field := range fields[1:] {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return "", fmt.Errorf("invalid structured data param %q", field)
+		}
+		value := strings.Trim(kv[1], `"`)
+		b.WriteByte(' ')
+		b.WriteString(kv[0])
+		b.WriteString(`="`)
+		b.WriteString(escapeSDValue(value))
+		b.WriteString(`"`)
+	}
+
+	b.WriteByte(']')
+	return b.String(), nil
 }
 
-// SendTCP sends the syslog message using TCP
-func (s *SyslogClient) SendTCP(message string) error {
-	// Create TCP connection
+// splitSDFields splits an SD-ELEMENT body on unquoted spaces
+func splitSDFields(s string) []string {
+	var fields []string
+	var cur strings.Builder
+	inQuotes := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+			cur.WriteByte(c)
+		case c == ' ' && !inQuotes:
+			if cur.Len() > 0 {
+				fields = append(fields, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	if cur.Len() > 0 {
+		fields = append(fields, cur.String())
+	}
+
+	return fields
+}
+
+// escapeSDValue backslash-escapes the characters RFC 5424 requires
+// escaped inside a PARAM-VALUE: '\\', '"' and ']'
+func escapeSDValue(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	return replacer.Replace(value)
+}
+
+// frameTCPMessage wraps message for the wire according to the configured
+// framing: RFC 6587 non-transparent framing (trailing newline) or RFC 5425
+// octet-counting framing ("<length> <message>")
+func (s *SyslogClient) frameTCPMessage(message string) string {
+	if s.config.Framing == "octet-counting" {
+		return fmt.Sprintf("%d %s", len(message), message)
+	}
+	return message + "\n"
+}
+
+// buildTLSConfig assembles a *tls.Config from the configured CA bundle,
+// optional client keypair, and server name / verification overrides
+func (s *SyslogClient) buildTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         s.config.TLSServerName,
+		InsecureSkipVerify: s.config.TLSSkipVerify,
+	}
+
+	if s.config.TLSCACert != "" {
+		caCert, err := os.ReadFile(s.config.TLSCACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate %s: %v", s.config.TLSCACert, err)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate %s", s.config.TLSCACert)
+		}
+		tlsConfig.RootCAs = caPool
+	}
+
+	if s.config.TLSCert != "" || s.config.TLSKey != "" {
+		cert, err := tls.LoadX509KeyPair(s.config.TLSCert, s.config.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client keypair: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// dialConn establishes a new connection for the configured transport
+// (udp, tcp, or tcp+tls)
+func (s *SyslogClient) dialConn() (net.Conn, error) {
 	serverAddr := fmt.Sprintf("%s:%d", s.config.Address, s.config.Port)
-	conn, err := net.DialTimeout("tcp", serverAddr, 10*time.Second)
-	if err != nil {
-		return fmt.Errorf("failed to connect to TCP server: %v", err)
+
+	switch s.config.Transport {
+	case "udp":
+		udpAddr, err := net.ResolveUDPAddr("udp", serverAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve UDP address %s: %v", serverAddr, err)
+		}
+		conn, err := net.DialUDP("udp", nil, udpAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to UDP server: %v", err)
+		}
+		return conn, nil
+	case "tcp":
+		conn, err := net.DialTimeout("tcp", serverAddr, 10*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to TCP server: %v", err)
+		}
+		return conn, nil
+	case "tcp+tls":
+		tlsConfig, err := s.buildTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build TLS config: %v", err)
+		}
+		dialer := &net.Dialer{Timeout: 10 * time.Second}
+		conn, err := tls.DialWithDialer(dialer, "tcp", serverAddr, tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to TLS server: %v", err)
+		}
+		return conn, nil
+	default:
+		return nil, fmt.Errorf("unsupported transport: %s", s.config.Transport)
+	}
+}
+
+// writeToConn writes message to conn, applying TCP framing for stream
+// transports; UDP messages are written as-is, one per datagram.
+func (s *SyslogClient) writeToConn(conn net.Conn, message string) error {
+	payload := message
+	if s.config.Transport != "udp" {
+		payload = s.frameTCPMessage(message)
 	}
-	defer conn.Close()
 
-	// Set write timeout
 	conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	_, err := conn.Write([]byte(payload))
+	return err
+}
+
+// ensureConn returns the persistent connection, dialing it lazily if this
+// is the first call. Callers must hold s.mu.
+func (s *SyslogClient) ensureConn() (net.Conn, error) {
+	if s.conn != nil {
+		return s.conn, nil
+	}
 
-	// Send message (TCP syslog messages often end with newline)
-	messageWithNewline := message + "\n"
-	_, err = conn.Write([]byte(messageWithNewline))
+	conn, err := s.dialConn()
 	if err != nil {
-		return fmt.Errorf("failed to send TCP message: %v", err)
+		return nil, err
+	}
+	s.conn = conn
+	return s.conn, nil
+}
+
+// closeConnLocked closes and clears the persistent connection. Callers must
+// hold s.mu.
+func (s *SyslogClient) closeConnLocked() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// sendOnConn writes message over the persistent connection, dialing it
+// lazily on first use. On a write failure it closes the connection,
+// redials once, and retries the write exactly once before giving up --
+// mirroring the deferred-connection and one-shot retry approach used by
+// Go's log/syslog package.
+func (s *SyslogClient) sendOnConn(message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conn, err := s.ensureConn()
+	if err != nil {
+		return err
+	}
+
+	if err := s.writeToConn(conn, message); err != nil {
+		s.closeConnLocked()
+
+		conn, err = s.dialConn()
+		if err != nil {
+			return fmt.Errorf("failed to reconnect after write failure: %v", err)
+		}
+		s.conn = conn
+
+		if err := s.writeToConn(conn, message); err != nil {
+			s.closeConnLocked()
+			return fmt.Errorf("failed to send message after reconnect: %v", err)
+		}
 	}
 
 	return nil
 }
 
-// Send sends the syslog message using the configured transport
+// Close closes the persistent connection, if one is open
+func (s *SyslogClient) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// Send formats and sends the configured message using the configured
+// transport, reusing (and lazily establishing) the persistent connection.
 func (s *SyslogClient) Send() error {
-	// Validate configuration
 	if err := s.validateConfig(); err != nil {
 		return err
 	}
 
-	// Format message
 	message, err := s.formatMessage()
 	if err != nil {
 		return fmt.Errorf("failed to format message: %v", err)
 	}
 
-	// Debug output if enabled
 	if os.Getenv("SYSLOG_DEBUG") == "1" {
 		fmt.Printf("Debug: Sending message: %s\n", message)
 		fmt.Printf("Debug: Target: %s:%d (%s)\n", s.config.Address, s.config.Port, s.config.Transport)
 	}
 
-	// Send message based on transport
-	switch s.config.Transport {
-	case "udp":
-		return s.SendUDP(message)
-	case "tcp":
-		return s.SendTCP(message)
-	default:
-		return fmt.Errorf("unsupported transport: %s", s.config.Transport)
+	return s.sendOnConn(message)
+}
+
+// SendMany formats and sends each message in order over a single reused
+// connection, redialing (once) if the server drops the connection between
+// messages.
+func (s *SyslogClient) SendMany(messages []string) error {
+	if err := s.validateConfig(); err != nil {
+		return err
+	}
+
+	for _, text := range messages {
+		message, err := s.formatMessageText(text)
+		if err != nil {
+			return fmt.Errorf("failed to format message: %v", err)
+		}
+
+		if os.Getenv("SYSLOG_DEBUG") == "1" {
+			fmt.Printf("Debug: Sending message: %s\n", message)
+		}
+
+		if err := s.sendOnConn(message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// batchLineOverride holds the optional per-line overrides accepted in
+// JSON-lines batch input, merged over the CLI-supplied defaults
+type batchLineOverride struct {
+	Facility *int   `json:"facility,omitempty"`
+	Severity *int   `json:"severity,omitempty"`
+	Program  string `json:"program,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// parseBatchLine interprets a batch input line as a JSON-lines override
+// object when it looks like JSON, otherwise treats the whole line as the
+// message text
+func parseBatchLine(line string) (batchLineOverride, error) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "{") {
+		var override batchLineOverride
+		if err := json.Unmarshal([]byte(trimmed), &override); err != nil {
+			return batchLineOverride{}, fmt.Errorf("failed to parse JSON line: %v", err)
+		}
+		return override, nil
 	}
+
+	return batchLineOverride{Message: line}, nil
+}
+
+// formatBatchLine formats a batch line's message using s's configuration
+// with the line's overrides merged on top
+func (s *SyslogClient) formatBatchLine(override batchLineOverride) (string, error) {
+	cfg := s.config
+	if override.Facility != nil {
+		if err := validateFacility(*override.Facility); err != nil {
+			return "", err
+		}
+		cfg.Facility = *override.Facility
+	}
+	if override.Severity != nil {
+		if err := validateSeverity(*override.Severity); err != nil {
+			return "", err
+		}
+		cfg.Severity = *override.Severity
+	}
+	if override.Program != "" {
+		cfg.Program = override.Program
+	}
+
+	text := cfg.Message
+	if override.Message != "" {
+		text = override.Message
+	}
+
+	formatter := &SyslogClient{config: cfg}
+	return formatter.formatMessageText(text)
+}
+
+// runBatch reads one message per line from r and sends each as a separate
+// syslog record. Messages are throttled to ratePerSecond when positive, and
+// fanned out across workerCount concurrent UDP senders when the configured
+// transport is UDP and workerCount > 1; other transports always send
+// serially over a single reused connection.
+func runBatch(config SyslogConfig, r io.Reader, ratePerSecond int, workerCount int) error {
+	// validateConfig requires a non-empty Message; batch lines supply their
+	// own text, so a placeholder is used purely to pass validation and is
+	// never sent as-is.
+	validated := config
+	validated.Message = "batch"
+	validator := NewSyslogClient(validated)
+	if err := validator.validateConfig(); err != nil {
+		return err
+	}
+	config = validator.config
+	config.Message = ""
+
+	workers := 1
+	if config.Transport == "udp" && workerCount > 1 {
+		workers = workerCount
+	}
+
+	var ticker *time.Ticker
+	if ratePerSecond > 0 {
+		ticker = time.NewTicker(time.Second / time.Duration(ratePerSecond))
+		defer ticker.Stop()
+	}
+
+	lines := make(chan string, workers)
+	errs := make(chan error, workers)
+
+	var collected []error
+	errsDone := make(chan struct{})
+	go func() {
+		defer close(errsDone)
+		for err := range errs {
+			collected = append(collected, err)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := NewSyslogClient(config)
+			defer client.Close()
+
+			for line := range lines {
+				override, err := parseBatchLine(line)
+				if err != nil {
+					errs <- err
+					continue
+				}
+
+				message, err := client.formatBatchLine(override)
+				if err != nil {
+					errs <- fmt.Errorf("failed to format message: %v", err)
+					continue
+				}
+
+				if os.Getenv("SYSLOG_DEBUG") == "1" {
+					fmt.Printf("Debug: Sending message: %s\n", message)
+				}
+
+				if err := client.sendOnConn(message); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if ticker != nil {
+			<-ticker.C
+		}
+		lines <- line
+	}
+	close(lines)
+	wg.Wait()
+	close(errs)
+	<-errsDone
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %v", err)
+	}
+
+	if len(collected) > 0 {
+		return collected[0]
+	}
+
+	return nil
 }
 
 // printUsage prints the usage information
@@ -200,8 +829,16 @@ func printUsage() {
 	fmt.Printf("  %s -facility 4 -severity 1 -message \"Security alert\"\n", os.Args[0])
 	fmt.Printf("  %s -hostname \"custom-host\" -message \"Message with custom hostname\"\n", os.Args[0])
 	fmt.Printf("  %s -program \"my-app\" -message \"Message with custom program\"\n", os.Args[0])
+	fmt.Printf("  %s -format rfc5424 -sd \"exampleSDID@32473 iut=\\\"3\\\"\" -message \"Structured message\"\n", os.Args[0])
+	fmt.Printf("  %s -transport tcp+tls -tls-ca-cert ca.pem -message \"Secure message\"\n", os.Args[0])
+	fmt.Printf("  tail -F app.log | %s -batch -transport tcp -program myapp\n", os.Args[0])
+	fmt.Printf("  %s -format cef -cef-vendor Acme -cef-product Gateway -cef-version 1.0 -signature-id 100 -name \"Blocked\" -ext src=10.0.0.1 -message \"traffic blocked\"\n", os.Args[0])
 	fmt.Printf("\nFacilities (0-23): 0=kernel, 1=user, 2=mail, 3=daemon, 4=security, 16-23=local0-7\n")
 	fmt.Printf("Severities (0-7): 0=emergency, 1=alert, 2=critical, 3=error, 4=warning, 5=notice, 6=info, 7=debug\n")
+	fmt.Printf("Formats: %s (default rfc3164)\n", strings.Join(registeredFormatterNames(), ", "))
+	fmt.Printf("Batch mode: each input line is sent as a separate message; lines may instead be\n")
+	fmt.Printf("JSON objects like {\"severity\":3,\"program\":\"foo\",\"message\":\"...\"} to override\n")
+	fmt.Printf("the CLI defaults per-message.\n")
 }
 
 func main() {
@@ -209,24 +846,53 @@ func main() {
 	var config SyslogConfig
 	var showHelp bool
 	var showVersion bool
+	var sdFlags stringSliceFlag
+	var extFlags stringSliceFlag
 
 	flag.StringVar(&config.Address, "address", "localhost", "Syslog server address")
 	flag.IntVar(&config.Port, "port", 514, "Syslog server port")
-	flag.StringVar(&config.Transport, "transport", "udp", "Transport protocol (udp or tcp)")
+	flag.StringVar(&config.Transport, "transport", "udp", "Transport protocol (udp, tcp, or tcp+tls)")
 	flag.IntVar(&config.Facility, "facility", 16, "Syslog facility (0-23)")
 	flag.IntVar(&config.Severity, "severity", 6, "Syslog severity (0-7)")
 	flag.StringVar(&config.Message, "message", "", "Message to send (required)")
 	flag.StringVar(&config.Hostname, "hostname", "", "Custom hostname (default: system hostname)")
 	flag.StringVar(&config.Program, "program", "", "Custom program/tag name (default: syslog-sender)")
+	flag.StringVar(&config.Format, "format", "rfc3164", "Message format (rfc3164, rfc5424, rfc5424micro, cef, or leef)")
+	flag.StringVar(&config.MsgID, "msgid", "", "RFC 5424 MSGID field (default: NILVALUE)")
+	flag.Var(&sdFlags, "sd", "RFC 5424 structured data element, e.g. 'id@ent key=\"val\"' (repeatable)")
+	flag.StringVar(&config.CEFVendor, "cef-vendor", "", "CEF/LEEF Device Vendor field")
+	flag.StringVar(&config.CEFProduct, "cef-product", "", "CEF/LEEF Device Product field")
+	flag.StringVar(&config.CEFVersion, "cef-version", "", "CEF/LEEF Device Version field")
+	flag.StringVar(&config.SignatureID, "signature-id", "", "CEF Signature ID / LEEF Event ID field")
+	flag.StringVar(&config.Name, "name", "", "CEF Name field")
+	flag.Var(&extFlags, "ext", "CEF/LEEF extension field as key=value (repeatable)")
+	flag.StringVar(&config.Framing, "framing", "non-transparent", "TCP framing (non-transparent or octet-counting)")
+	flag.StringVar(&config.TLSCACert, "tls-ca-cert", "", "Path to PEM CA certificate bundle for verifying the server")
+	flag.StringVar(&config.TLSCert, "tls-cert", "", "Path to PEM client certificate (for mutual TLS)")
+	flag.StringVar(&config.TLSKey, "tls-key", "", "Path to PEM client private key (for mutual TLS)")
+	flag.StringVar(&config.TLSServerName, "tls-server-name", "", "Server name for TLS certificate verification (default: -address)")
+	flag.BoolVar(&config.TLSSkipVerify, "tls-skip-verify", false, "Skip TLS certificate verification (insecure)")
 	flag.BoolVar(&showHelp, "help", false, "Show help message")
 	flag.BoolVar(&showVersion, "version", false, "Show version information")
 
+	var batchMode bool
+	var inputPath string
+	var rate int
+	var workers int
+	flag.BoolVar(&batchMode, "batch", false, "Read messages from -input (one per line) and send each as a separate record")
+	flag.StringVar(&inputPath, "input", "-", "Input file for batch mode (use '-' for stdin)")
+	flag.IntVar(&rate, "rate", 0, "Throttle batch sending to this many messages per second (0 = unlimited)")
+	flag.IntVar(&workers, "workers", 1, "Number of concurrent UDP senders to fan batch messages out across")
+
 	// Custom usage function
 	flag.Usage = printUsage
 
 	// Parse command line arguments
 	flag.Parse()
 
+	config.StructuredData = sdFlags
+	config.Extensions = extFlags
+
 	// Show version if requested
 	if showVersion {
 		fmt.Printf("%s version %s\n", AppName, AppVersion)
@@ -240,20 +906,43 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Check if message is provided
-	if config.Message == "" {
+	// Check if message is provided (batch mode supplies messages via -input instead)
+	if config.Message == "" && !batchMode {
 		fmt.Fprintf(os.Stderr, "Error: message is required\n\n")
 		printUsage()
 		os.Exit(1)
 	}
 
-	// Adjust default port for TCP if not explicitly set
-	if flag.Lookup("port").Value.String() == "514" && strings.ToLower(config.Transport) == "tcp" {
-		config.Port = 601
+	// Adjust default port for TCP/TLS if not explicitly set
+	if flag.Lookup("port").Value.String() == "514" {
+		switch strings.ToLower(config.Transport) {
+		case "tcp":
+			config.Port = 601
+		case "tcp+tls":
+			config.Port = 6514
+		}
+	}
+
+	if batchMode {
+		input := os.Stdin
+		if inputPath != "-" {
+			file, err := os.Open(inputPath)
+			if err != nil {
+				log.Fatalf("Failed to open input file: %v", err)
+			}
+			defer file.Close()
+			input = file
+		}
+
+		if err := runBatch(config, input, rate, workers); err != nil {
+			log.Fatalf("Batch send failed: %v", err)
+		}
+		return
 	}
 
 	// Create syslog client
 	client := NewSyslogClient(config)
+	defer client.Close()
 
 	// Send message
 	if err := client.Send(); err != nil {
@@ -2,10 +2,258 @@ package main
 
 import (
 	"fmt"
+	"net"
 	"strings"
 	"testing"
+	"time"
 )
 
+func TestParseBatchLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    batchLineOverride
+		wantErr bool
+	}{
+		{
+			name: "plain text line",
+			line: "application started",
+			want: batchLineOverride{Message: "application started"},
+		},
+		{
+			name: "json override",
+			line: `{"severity":3,"program":"foo","message":"disk full"}`,
+			want: batchLineOverride{Severity: intPtr(3), Program: "foo", Message: "disk full"},
+		},
+		{
+			name:    "invalid json",
+			line:    `{"severity":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBatchLine(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBatchLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Message != tt.want.Message || got.Program != tt.want.Program {
+				t.Errorf("parseBatchLine() = %+v, want %+v", got, tt.want)
+			}
+			if (got.Severity == nil) != (tt.want.Severity == nil) {
+				t.Errorf("parseBatchLine() Severity = %v, want %v", got.Severity, tt.want.Severity)
+			} else if got.Severity != nil && *got.Severity != *tt.want.Severity {
+				t.Errorf("parseBatchLine() Severity = %v, want %v", *got.Severity, *tt.want.Severity)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestFormatBatchLineOverrides(t *testing.T) {
+	config := SyslogConfig{
+		Facility: 16,
+		Severity: 6,
+		Message:  "default message",
+		Program:  "default-program",
+		Hostname: "test-host",
+	}
+	client := NewSyslogClient(config)
+
+	message, err := client.formatBatchLine(batchLineOverride{
+		Severity: intPtr(3),
+		Program:  "override-program",
+		Message:  "override message",
+	})
+	if err != nil {
+		t.Fatalf("formatBatchLine() error = %v", err)
+	}
+
+	expectedPriority := fmt.Sprintf("<%d>", 16*8+3)
+	if !strings.HasPrefix(message, expectedPriority) {
+		t.Errorf("expected priority %s, got: %s", expectedPriority, message)
+	}
+	if !strings.Contains(message, "override-program:") {
+		t.Errorf("expected overridden program in message: %s", message)
+	}
+	if !strings.Contains(message, "override message") {
+		t.Errorf("expected overridden message content: %s", message)
+	}
+}
+
+func TestFormatBatchLineDefaults(t *testing.T) {
+	config := SyslogConfig{
+		Facility: 16,
+		Severity: 6,
+		Message:  "default message",
+		Program:  "default-program",
+	}
+	client := NewSyslogClient(config)
+
+	message, err := client.formatBatchLine(batchLineOverride{})
+	if err != nil {
+		t.Fatalf("formatBatchLine() error = %v", err)
+	}
+	if !strings.Contains(message, "default-program:") {
+		t.Errorf("expected default program in message: %s", message)
+	}
+	if !strings.Contains(message, "default message") {
+		t.Errorf("expected default message content: %s", message)
+	}
+}
+
+func TestFormatBatchLineInvalidOverrides(t *testing.T) {
+	config := SyslogConfig{
+		Facility: 16,
+		Severity: 6,
+		Message:  "default message",
+		Program:  "default-program",
+	}
+
+	tests := []struct {
+		name     string
+		override batchLineOverride
+	}{
+		{"facility too high", batchLineOverride{Facility: intPtr(24)}},
+		{"facility negative", batchLineOverride{Facility: intPtr(-1)}},
+		{"severity too high", batchLineOverride{Severity: intPtr(8)}},
+		{"severity negative", batchLineOverride{Severity: intPtr(-1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewSyslogClient(config)
+			if _, err := client.formatBatchLine(tt.override); err == nil {
+				t.Errorf("formatBatchLine() error = nil, want error for %+v", tt.override)
+			}
+		})
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	ln, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start test UDP listener: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 2)
+	go func() {
+		buf := make([]byte, 4096)
+		for i := 0; i < 2; i++ {
+			n, _, err := ln.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			received <- string(buf[:n])
+		}
+	}()
+
+	addr := ln.LocalAddr().(*net.UDPAddr)
+	config := SyslogConfig{
+		Address:   "127.0.0.1",
+		Port:      addr.Port,
+		Transport: "udp",
+		Facility:  16,
+		Severity:  6,
+		Program:   "batch-test",
+	}
+
+	input := strings.NewReader("plain line\n" + `{"severity":3,"message":"json line"}` + "\n")
+	if err := runBatch(config, input, 0, 1); err != nil {
+		t.Fatalf("runBatch() error = %v", err)
+	}
+
+	for i, want := range []string{"plain line", "json line"} {
+		select {
+		case msg := <-received:
+			if !strings.Contains(msg, want) {
+				t.Errorf("message %d = %q, want to contain %q", i, msg, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for batch message %d", i)
+		}
+	}
+}
+
+func TestRunBatchMultipleErrorsNoHang(t *testing.T) {
+	ln, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start test UDP listener: %v", err)
+	}
+	defer ln.Close()
+
+	addr := ln.LocalAddr().(*net.UDPAddr)
+	config := SyslogConfig{
+		Address:   "127.0.0.1",
+		Port:      addr.Port,
+		Transport: "udp",
+		Facility:  16,
+		Severity:  6,
+		Program:   "batch-test",
+	}
+
+	input := strings.NewReader(strings.Repeat(`{"not valid json`+"\n", 5))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runBatch(config, input, 0, 1)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("runBatch() error = nil, want a parse error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("runBatch() did not return within timeout; likely deadlocked on errs channel")
+	}
+}
+
+func TestRunBatchInvalidSeverityOverride(t *testing.T) {
+	ln, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start test UDP listener: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		buf := make([]byte, 4096)
+		n, _, err := ln.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		received <- append([]byte(nil), buf[:n]...)
+	}()
+
+	addr := ln.LocalAddr().(*net.UDPAddr)
+	config := SyslogConfig{
+		Address:   "127.0.0.1",
+		Port:      addr.Port,
+		Transport: "udp",
+		Facility:  16,
+		Severity:  6,
+		Program:   "batch-test",
+	}
+
+	input := strings.NewReader(`{"severity":99,"message":"bad"}` + "\n")
+	if err := runBatch(config, input, 0, 1); err == nil {
+		t.Fatalf("runBatch() error = nil, want error for out-of-range severity")
+	}
+
+	select {
+	case msg := <-received:
+		t.Fatalf("expected no message to be sent, got: %s", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
 func TestSyslogConfig(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -303,6 +551,478 @@ func TestSpaceHandling(t *testing.T) {
 	}
 }
 
+func TestFormatMessageRFC5424(t *testing.T) {
+	config := SyslogConfig{
+		Address:   "localhost",
+		Port:      514,
+		Transport: "udp",
+		Facility:  4,
+		Severity:  1,
+		Message:   "security alert",
+		Hostname:  "test-host",
+		Program:   "test-program",
+		Format:    "rfc5424",
+		MsgID:     "ID47",
+	}
+
+	client := NewSyslogClient(config)
+	if err := client.validateConfig(); err != nil {
+		t.Fatalf("validateConfig() error = %v", err)
+	}
+
+	message, err := client.formatMessage()
+	if err != nil {
+		t.Fatalf("formatMessage() error = %v", err)
+	}
+
+	if !strings.HasPrefix(message, "<33>1 ") {
+		t.Errorf("expected priority/version prefix '<33>1 ', got: %s", message)
+	}
+	for _, expected := range []string{"test-host", "test-program", "ID47", "security alert"} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("formatMessage() = %v, expected to contain %v", message, expected)
+		}
+	}
+	// No structured data supplied, so NILVALUE should appear before MSG
+	if !strings.Contains(message, " - security alert") {
+		t.Errorf("expected NILVALUE structured data before MSG, got: %s", message)
+	}
+}
+
+func TestFormatMessageRFC5424Defaults(t *testing.T) {
+	config := SyslogConfig{
+		Address:   "localhost",
+		Port:      514,
+		Transport: "udp",
+		Facility:  16,
+		Severity:  6,
+		Message:   "test message",
+		Format:    "rfc5424",
+	}
+
+	client := NewSyslogClient(config)
+	message, err := client.formatMessage()
+	if err != nil {
+		t.Fatalf("formatMessage() error = %v", err)
+	}
+
+	// MSGID defaults to NILVALUE when unset
+	parts := strings.SplitN(message, " ", 8)
+	if len(parts) < 6 || parts[5] != NilValue {
+		t.Errorf("expected NILVALUE MSGID, got message: %s", message)
+	}
+}
+
+func TestFormatMessageRFC5424Micro(t *testing.T) {
+	config := SyslogConfig{
+		Address:   "localhost",
+		Port:      514,
+		Transport: "udp",
+		Facility:  16,
+		Severity:  6,
+		Message:   "test message",
+		Format:    "rfc5424micro",
+	}
+
+	client := NewSyslogClient(config)
+	message, err := client.formatMessage()
+	if err != nil {
+		t.Fatalf("formatMessage() error = %v", err)
+	}
+
+	parts := strings.SplitN(message, " ", 3)
+	if len(parts) < 2 {
+		t.Fatalf("unexpected message shape: %s", message)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, parts[1]); err != nil {
+		t.Errorf("expected RFC3339Nano timestamp, got %q: %v", parts[1], err)
+	}
+}
+
+func TestFormatMessageRFC5424BOM(t *testing.T) {
+	config := SyslogConfig{
+		Address:   "localhost",
+		Port:      514,
+		Transport: "udp",
+		Facility:  16,
+		Severity:  6,
+		Message:   "café",
+		Format:    "rfc5424",
+	}
+
+	client := NewSyslogClient(config)
+	message, err := client.formatMessage()
+	if err != nil {
+		t.Fatalf("formatMessage() error = %v", err)
+	}
+
+	if !strings.Contains(message, "\xEF\xBB\xBFcafé") {
+		t.Errorf("expected BOM-prefixed MSG for non-ASCII content, got: %s", message)
+	}
+}
+
+func TestBuildStructuredData(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "no entries",
+			entries: nil,
+			want:    NilValue,
+		},
+		{
+			name:    "single element",
+			entries: []string{`exampleSDID@32473 iut="3" eventSource="Application"`},
+			want:    `[exampleSDID@32473 iut="3" eventSource="Application"]`,
+		},
+		{
+			name:    "escapes special characters",
+			entries: []string{`exampleSDID@32473 path="a\b]c"d"`},
+			want:    `[exampleSDID@32473 path="a\\b\]c\"d"]`,
+		},
+		{
+			name:    "invalid param",
+			entries: []string{"exampleSDID@32473 notakeyvalue"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildStructuredData(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("buildStructuredData() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("buildStructuredData() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameTCPMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		framing string
+		message string
+		want    string
+	}{
+		{
+			name:    "non-transparent appends newline",
+			framing: "non-transparent",
+			message: "<134>hello",
+			want:    "<134>hello\n",
+		},
+		{
+			name:    "octet-counting prefixes length",
+			framing: "octet-counting",
+			message: "<134>hello",
+			want:    "10 <134>hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewSyslogClient(SyslogConfig{Framing: tt.framing})
+			got := client.frameTCPMessage(tt.message)
+			if got != tt.want {
+				t.Errorf("frameTCPMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConfigFormatAndFraming(t *testing.T) {
+	base := SyslogConfig{
+		Address:   "localhost",
+		Port:      514,
+		Transport: "udp",
+		Facility:  16,
+		Severity:  6,
+		Message:   "test message",
+	}
+
+	invalidFormat := base
+	invalidFormat.Format = "rfc9999"
+	if err := NewSyslogClient(invalidFormat).validateConfig(); err == nil {
+		t.Error("expected error for invalid format")
+	}
+
+	invalidFraming := base
+	invalidFraming.Framing = "chunked"
+	if err := NewSyslogClient(invalidFraming).validateConfig(); err == nil {
+		t.Error("expected error for invalid framing")
+	}
+
+	validRFC5424 := base
+	validRFC5424.Format = "rfc5424"
+	if err := NewSyslogClient(validRFC5424).validateConfig(); err != nil {
+		t.Errorf("validateConfig() error = %v, want nil", err)
+	}
+}
+
+func TestValidateConfigTLSTransport(t *testing.T) {
+	config := SyslogConfig{
+		Address:   "localhost",
+		Port:      6514,
+		Transport: "tcp+tls",
+		Facility:  16,
+		Severity:  6,
+		Message:   "test message",
+	}
+
+	client := NewSyslogClient(config)
+	if err := client.validateConfig(); err != nil {
+		t.Errorf("validateConfig() error = %v, want nil for tcp+tls transport", err)
+	}
+}
+
+func TestBuildTLSConfigDefaults(t *testing.T) {
+	config := SyslogConfig{
+		TLSServerName: "syslog.example.com",
+		TLSSkipVerify: true,
+	}
+
+	client := NewSyslogClient(config)
+	tlsConfig, err := client.buildTLSConfig()
+	if err != nil {
+		t.Fatalf("buildTLSConfig() error = %v", err)
+	}
+
+	if tlsConfig.ServerName != "syslog.example.com" {
+		t.Errorf("expected ServerName %q, got %q", "syslog.example.com", tlsConfig.ServerName)
+	}
+	if !tlsConfig.InsecureSkipVerify {
+		t.Error("expected InsecureSkipVerify to be true")
+	}
+	if tlsConfig.RootCAs != nil {
+		t.Error("expected nil RootCAs when no CA cert is configured")
+	}
+}
+
+func TestBuildTLSConfigMissingCACert(t *testing.T) {
+	config := SyslogConfig{TLSCACert: "/nonexistent/ca.pem"}
+
+	client := NewSyslogClient(config)
+	if _, err := client.buildTLSConfig(); err == nil {
+		t.Error("expected error for missing CA certificate file")
+	}
+}
+
+func TestSendManyReconnectsAfterServerDrop(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start test listener: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 2)
+	go func() {
+		for i := 0; i < 2; i++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			buf := make([]byte, 4096)
+			n, _ := conn.Read(buf)
+			received <- string(buf[:n])
+
+			if i == 0 {
+				// Force an RST on close so the client's next write on this
+				// connection fails and triggers the reconnect-and-retry path.
+				if tcpConn, ok := conn.(*net.TCPConn); ok {
+					tcpConn.SetLinger(0)
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	config := SyslogConfig{
+		Address:   "127.0.0.1",
+		Port:      addr.Port,
+		Transport: "tcp",
+		Facility:  16,
+		Severity:  6,
+		Message:   "placeholder",
+	}
+
+	client := NewSyslogClient(config)
+	defer client.Close()
+
+	if err := client.SendMany([]string{"first message"}); err != nil {
+		t.Fatalf("SendMany() error = %v", err)
+	}
+
+	// Give the server time to close (and RST) the connection before the
+	// client attempts its next write on it.
+	time.Sleep(100 * time.Millisecond)
+
+	if err := client.SendMany([]string{"second message"}); err != nil {
+		t.Fatalf("SendMany() error = %v", err)
+	}
+
+	expected := []string{"first message", "second message"}
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-received:
+			if !strings.Contains(msg, expected[i]) {
+				t.Errorf("expected message %d to contain %q, got: %s", i, expected[i], msg)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for message %d", i)
+		}
+	}
+}
+
+func TestClose(t *testing.T) {
+	client := NewSyslogClient(SyslogConfig{Transport: "udp"})
+
+	// Close on a client that never dialed should be a no-op
+	if err := client.Close(); err != nil {
+		t.Errorf("Close() on unused client error = %v, want nil", err)
+	}
+}
+
+func TestFormatMessageCEF(t *testing.T) {
+	config := SyslogConfig{
+		Address:     "localhost",
+		Port:        514,
+		Transport:   "udp",
+		Facility:    4,
+		Severity:    5,
+		Message:     "traffic blocked",
+		Format:      "cef",
+		CEFVendor:   "Acme",
+		CEFProduct:  "Gateway",
+		CEFVersion:  "1.0",
+		SignatureID: "100",
+		Name:        "Blocked traffic",
+		Extensions:  []string{"src=10.0.0.1", "dst=10.0.0.2"},
+	}
+
+	client := NewSyslogClient(config)
+	message, err := client.formatMessage()
+	if err != nil {
+		t.Fatalf("formatMessage() error = %v", err)
+	}
+
+	if !strings.Contains(message, "CEF:0|Acme|Gateway|1.0|100|Blocked traffic|5|") {
+		t.Errorf("unexpected CEF header, got: %s", message)
+	}
+	if !strings.Contains(message, "5|src=10.0.0.1 dst=10.0.0.2 msg=traffic blocked") {
+		t.Errorf("expected space-delimited extension, got: %s", message)
+	}
+}
+
+func TestFormatMessageCEFEscaping(t *testing.T) {
+	config := SyslogConfig{
+		Facility:    16,
+		Severity:    6,
+		Message:     "line1\nline2",
+		Format:      "cef",
+		SignatureID: "id|with|pipes",
+		Extensions:  []string{`path=a\b=c`},
+	}
+
+	client := NewSyslogClient(config)
+	message, err := client.formatMessage()
+	if err != nil {
+		t.Fatalf("formatMessage() error = %v", err)
+	}
+
+	if !strings.Contains(message, `id\|with\|pipes`) {
+		t.Errorf("expected escaped pipes in signature ID, got: %s", message)
+	}
+	if !strings.Contains(message, `path=a\\b\=c`) {
+		t.Errorf("expected escaped backslash/equals in extension, got: %s", message)
+	}
+	if !strings.Contains(message, `msg=line1\nline2`) {
+		t.Errorf("expected escaped newline in msg extension, got: %s", message)
+	}
+}
+
+func TestFormatMessageLEEF(t *testing.T) {
+	config := SyslogConfig{
+		Facility:    16,
+		Severity:    6,
+		Message:     "login failed",
+		Format:      "leef",
+		CEFVendor:   "Acme",
+		CEFProduct:  "Gateway",
+		CEFVersion:  "1.0",
+		SignatureID: "200",
+		Extensions:  []string{"user=alice"},
+	}
+
+	client := NewSyslogClient(config)
+	message, err := client.formatMessage()
+	if err != nil {
+		t.Fatalf("formatMessage() error = %v", err)
+	}
+
+	if !strings.Contains(message, "LEEF:2.0|Acme|Gateway|1.0|200|^|") {
+		t.Errorf("unexpected LEEF header, got: %s", message)
+	}
+	if !strings.Contains(message, "user=alice^msg=login failed") {
+		t.Errorf("expected caret-delimited extension, got: %s", message)
+	}
+}
+
+func TestFormatMessageInvalidExtension(t *testing.T) {
+	config := SyslogConfig{
+		Facility:   16,
+		Severity:   6,
+		Message:    "test",
+		Format:     "cef",
+		Extensions: []string{"not-a-key-value-pair"},
+	}
+
+	client := NewSyslogClient(config)
+	if _, err := client.formatMessage(); err == nil {
+		t.Error("expected error for malformed -ext entry")
+	}
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	called := false
+	RegisterFormatter("test-custom-format", formatterFunc(func(cfg SyslogConfig, now time.Time) (string, error) {
+		called = true
+		return "custom:" + cfg.Message, nil
+	}))
+
+	client := NewSyslogClient(SyslogConfig{
+		Facility: 16,
+		Severity: 6,
+		Message:  "hello",
+		Format:   "test-custom-format",
+	})
+
+	message, err := client.formatMessage()
+	if err != nil {
+		t.Fatalf("formatMessage() error = %v", err)
+	}
+	if !called {
+		t.Error("expected registered formatter to be invoked")
+	}
+	if message != "custom:hello" {
+		t.Errorf("formatMessage() = %q, want %q", message, "custom:hello")
+	}
+}
+
+// formatterFunc adapts a plain function to the Formatter interface for tests
+type formatterFunc func(cfg SyslogConfig, now time.Time) (string, error)
+
+func (f formatterFunc) Format(cfg SyslogConfig, now time.Time) (string, error) {
+	return f(cfg, now)
+}
+
 func TestVersionConstants(t *testing.T) {
 	if AppName == "" {
 		t.Error("AppName constant is empty")